@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// defaultDaemonPollInterval is how often the daemon samples idle time.
+const defaultDaemonPollInterval = 5 * time.Second
+
+// DaemonOptions configures a run of the idle-monitoring daemon.
+type DaemonOptions struct {
+	IdleThreshold time.Duration
+	Resume        bool
+	PollInterval  time.Duration
+}
+
+// RunDaemon polls system idle time and, once it exceeds opts.IdleThreshold,
+// clocks the current session out; if opts.Resume is set, activity resuming
+// afterward clocks back in under the same category. Auto transitions go
+// through clockInOut, so they're subject to the same alternation-invariant
+// checks as manual ones, and key off isActiveAction so a pomodoro work
+// interval is clocked out (and resumed) the same way a plain clock-in is.
+// It blocks until it returns an error (e.g. idle detection isn't supported
+// on this platform); stop it with SIGINT/SIGTERM.
+func RunDaemon(app *ClockApp, opts DaemonOptions) error {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultDaemonPollInterval
+	}
+
+	if err := recoverFromCrash(app, opts.IdleThreshold); err != nil {
+		return err
+	}
+
+	var autoClockedOutCategory string
+	for {
+		idle, err := systemIdleTime()
+		if err != nil {
+			return fmt.Errorf("error reading idle time: %v", err)
+		}
+		if err := app.Store.SetHeartbeat(time.Now().Add(-idle)); err != nil {
+			return fmt.Errorf("error recording heartbeat: %v", err)
+		}
+
+		records, err := app.readRows(1)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case idle >= opts.IdleThreshold:
+			if len(records) > 0 && isActiveAction(records[0].action) {
+				category := records[0].category
+				if err := app.clockInOut(clockOutAction, category); err != nil {
+					log.Printf("auto clock-out failed: %v", err)
+				} else {
+					log.Printf("auto clocked out of %q after %v idle", category, idle.Round(time.Second))
+					autoClockedOutCategory = category
+				}
+			}
+		case opts.Resume && autoClockedOutCategory != "":
+			if len(records) == 0 || !isActiveAction(records[0].action) {
+				if err := app.clockInOut(clockInAction, autoClockedOutCategory); err != nil {
+					log.Printf("auto clock-in failed: %v", err)
+				} else {
+					log.Printf("auto clocked back in to %q", autoClockedOutCategory)
+				}
+			}
+			autoClockedOutCategory = ""
+		}
+
+		time.Sleep(opts.PollInterval)
+	}
+}
+
+// recoverFromCrash checks the last heartbeat left by a previous daemon run
+// against the current record: if it's older than idleThreshold and the
+// session is still clocked in, the previous run almost certainly crashed
+// while idle, so it clocks out now instead of silently counting the gap as
+// active time.
+func recoverFromCrash(app *ClockApp, idleThreshold time.Duration) error {
+	lastActive, ok, err := app.Store.GetHeartbeat()
+	if err != nil {
+		return fmt.Errorf("error reading last heartbeat: %v", err)
+	}
+	if !ok || time.Since(lastActive) < idleThreshold {
+		return nil
+	}
+
+	records, err := app.readRows(1)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 || !isActiveAction(records[0].action) {
+		return nil
+	}
+
+	category := records[0].category
+	if err := app.clockInOut(clockOutAction, category); err != nil {
+		return fmt.Errorf("error auto clocking out after crash recovery: %v", err)
+	}
+	log.Printf("daemon restarted after a crash; auto clocked out of %q (idle since %s)", category, lastActive.Format(displayTimeLayout))
+	return nil
+}
+
+// DaemonStatus is a one-shot snapshot of idle time and what the daemon
+// would do next, for `clock daemon status`.
+type DaemonStatus struct {
+	Idle          time.Duration
+	IdleThreshold time.Duration
+	NextAction    string
+}
+
+// GetDaemonStatus reports the current idle time and what RunDaemon would do
+// about it right now, without actually running the daemon loop.
+func GetDaemonStatus(app *ClockApp, idleThreshold time.Duration) (*DaemonStatus, error) {
+	idle, err := systemIdleTime()
+	if err != nil {
+		return nil, err
+	}
+	records, err := app.readRows(1)
+	if err != nil {
+		return nil, err
+	}
+
+	next := "none"
+	if len(records) > 0 && isActiveAction(records[0].action) {
+		if idle >= idleThreshold {
+			next = fmt.Sprintf("clock out of %q now (idle threshold already exceeded)", records[0].category)
+		} else {
+			next = fmt.Sprintf("clock out of %q in %v", records[0].category, (idleThreshold - idle).Round(time.Second))
+		}
+	}
+
+	return &DaemonStatus{Idle: idle, IdleThreshold: idleThreshold, NextAction: next}, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withFakeIdle(t *testing.T, d time.Duration) {
+	t.Helper()
+	prev := systemIdleTime
+	systemIdleTime = func() (time.Duration, error) { return d, nil }
+	t.Cleanup(func() { systemIdleTime = prev })
+}
+
+func TestGetDaemonStatusNoRecords(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+	withFakeIdle(t, time.Minute)
+
+	status, err := GetDaemonStatus(app, 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "none", status.NextAction)
+}
+
+func TestGetDaemonStatusClockedIn(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+	assert.NoError(t, app.writeRow(clockInAction, "work"))
+
+	withFakeIdle(t, 20*time.Minute)
+	status, err := GetDaemonStatus(app, 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Contains(t, status.NextAction, "clock out of \"work\" now")
+
+	withFakeIdle(t, 5*time.Minute)
+	status, err = GetDaemonStatus(app, 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Contains(t, status.NextAction, "clock out of \"work\" in")
+}
+
+func TestGetDaemonStatusClockedInPomodoro(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+	assert.NoError(t, app.writeRow(pomoInAction, "work"))
+
+	withFakeIdle(t, 20*time.Minute)
+	status, err := GetDaemonStatus(app, 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Contains(t, status.NextAction, "clock out of \"work\" now")
+}
+
+func TestRecoverFromCrashClocksOutStaleSession(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assert.NoError(t, app.writeRow(clockInAction, "work"))
+	assert.NoError(t, app.Store.SetHeartbeat(time.Now().Add(-time.Hour)))
+
+	assert.NoError(t, recoverFromCrash(app, 15*time.Minute))
+
+	records, err := app.readRows(1)
+	assert.NoError(t, err)
+	assert.Equal(t, clockOutAction, records[0].action)
+}
+
+func TestRecoverFromCrashNoopWhenRecent(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assert.NoError(t, app.writeRow(clockInAction, "work"))
+	assert.NoError(t, app.Store.SetHeartbeat(time.Now()))
+
+	assert.NoError(t, recoverFromCrash(app, 15*time.Minute))
+
+	records, err := app.readRows(1)
+	assert.NoError(t, err)
+	assert.Equal(t, clockInAction, records[0].action)
+}
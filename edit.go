@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeEpsilon is the slack allowed when checking that record times are
+// monotonically non-decreasing, to absorb clock jitter between inserts.
+const timeEpsilon = time.Second
+
+// UpdateRecord changes the time, action, and/or category of the record
+// with the given id. Any nil field is left unchanged. It re-reads the
+// neighboring records and aborts with a descriptive error if the result
+// would violate the invariant that actions strictly alternate and times
+// are monotonically non-decreasing. The check and the write are separate
+// store calls, not one transaction: with the bolt backend the lock file
+// keeps other clock processes out for the whole run, but with the SQLite
+// backend nothing stops another process writing between them.
+func (app *ClockApp) UpdateRecord(id int, newTime *time.Time, action *clockAction, category *string) error {
+	record, err := getRecord(app.Store, id)
+	if err != nil {
+		return err
+	}
+
+	if newTime != nil {
+		record.time = newTime.UTC().Format(time.RFC3339)
+	}
+	if action != nil {
+		record.action = *action
+	}
+	if category != nil {
+		record.category = *category
+	}
+
+	prev, next, err := storeNeighbors(app.Store, id)
+	if err != nil {
+		return err
+	}
+	if err := checkAlternation(prev, record, next); err != nil {
+		return err
+	}
+
+	return app.Store.UpdateRow(record)
+}
+
+// DeleteRecord removes the record with the given id, aborting with a
+// descriptive error if doing so would leave the remaining sequence
+// violating the alternation invariant.
+func (app *ClockApp) DeleteRecord(id int) error {
+	if _, err := getRecord(app.Store, id); err != nil {
+		return err
+	}
+
+	prev, next, err := storeNeighbors(app.Store, id)
+	if err != nil {
+		return err
+	}
+	if next != nil {
+		if prev != nil {
+			if err := checkAlternation(nil, *prev, next); err != nil {
+				return fmt.Errorf("deleting record %d would leave an invalid sequence: %v", id, err)
+			}
+		} else if !isActiveAction(next.action) {
+			return fmt.Errorf("deleting record %d would leave record %d (%q) starting the sequence, but a sequence must start with an active action", id, next.id, next.action)
+		}
+	}
+
+	return app.Store.DeleteRow(id)
+}
+
+// AmendRecord modifies the most recent record, leaving any nil field
+// unchanged.
+func (app *ClockApp) AmendRecord(newTime *time.Time, action *clockAction, category *string) error {
+	records, err := app.readRows(1)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no records to amend")
+	}
+	return app.UpdateRecord(records[0].id, newTime, action, category)
+}
+
+// parseEditFlags turns the --time/--action/--category flag strings shared
+// by the edit and amend commands into the pointers UpdateRecord expects,
+// leaving a field nil when its flag was not set. timeFlag is parsed
+// against loc, the configured display timezone, not necessarily the
+// host's.
+func parseEditFlags(timeFlag, actionFlag, categoryFlag string, loc *time.Location) (*time.Time, *clockAction, *string, error) {
+	var newTime *time.Time
+	if timeFlag != "" {
+		parsed, err := time.ParseInLocation(legacyTimeLayout, timeFlag, loc)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error parsing --time: %v", err)
+		}
+		newTime = &parsed
+	}
+
+	var action *clockAction
+	switch actionFlag {
+	case "":
+	case string(clockInAction):
+		a := clockInAction
+		action = &a
+	case string(clockOutAction):
+		a := clockOutAction
+		action = &a
+	default:
+		return nil, nil, nil, fmt.Errorf("invalid --action %q: must be %q or %q", actionFlag, clockInAction, clockOutAction)
+	}
+
+	var category *string
+	if categoryFlag != "" {
+		category = &categoryFlag
+	}
+
+	return newTime, action, category, nil
+}
+
+func getRecord(store Store, id int) (Record, error) {
+	records, err := store.RangeByTime(farPast, farFuture, "")
+	if err != nil {
+		return Record{}, fmt.Errorf("error reading record %d: %v", id, err)
+	}
+	for _, record := range records {
+		if record.id == id {
+			return record, nil
+		}
+	}
+	return Record{}, fmt.Errorf("no record with id %d", id)
+}
+
+// checkAlternation verifies that, with record placed between prev and
+// next, actions strictly alternate and times are monotonically
+// non-decreasing (within timeEpsilon).
+func checkAlternation(prev *Record, record Record, next *Record) error {
+	recordTime, err := parseRecordTime(record.time)
+	if err != nil {
+		return fmt.Errorf("error parsing record time: %v", err)
+	}
+
+	if prev != nil {
+		if isActiveAction(prev.action) == isActiveAction(record.action) {
+			return fmt.Errorf("would create two consecutive %q actions", record.action)
+		}
+		prevTime, err := parseRecordTime(prev.time)
+		if err != nil {
+			return fmt.Errorf("error parsing previous record time: %v", err)
+		}
+		if recordTime.Before(prevTime.Add(-timeEpsilon)) {
+			return fmt.Errorf("time %s precedes previous record's time %s", record.time, prev.time)
+		}
+	}
+
+	if next != nil {
+		if isActiveAction(next.action) == isActiveAction(record.action) {
+			return fmt.Errorf("would create two consecutive %q actions", record.action)
+		}
+		nextTime, err := parseRecordTime(next.time)
+		if err != nil {
+			return fmt.Errorf("error parsing next record time: %v", err)
+		}
+		if nextTime.Before(recordTime.Add(-timeEpsilon)) {
+			return fmt.Errorf("next record's time %s precedes new time %s", next.time, record.time)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateRecordCategory(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assert.NoError(t, app.writeRow(clockInAction, "work"))
+	records, err := app.readRows(1)
+	assert.NoError(t, err)
+	id := records[0].id
+
+	newCategory := "personal"
+	assert.NoError(t, app.UpdateRecord(id, nil, nil, &newCategory))
+
+	records, err = app.readRows(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "personal", records[0].category)
+}
+
+func TestUpdateRecordRejectsConsecutiveActions(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assert.NoError(t, app.writeRow(clockInAction, "work"))
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, app.writeRow(clockOutAction, "work"))
+
+	records, err := app.readRows(2)
+	assert.NoError(t, err)
+	outID := records[0].id
+
+	badAction := clockInAction
+	err = app.UpdateRecord(outID, nil, &badAction, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "consecutive")
+}
+
+func TestUpdateRecordRejectsOutOfOrderTime(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertRecordAt(t, app, base, clockInAction, "work")
+	insertRecordAt(t, app, base.Add(time.Hour), clockOutAction, "work")
+
+	records, err := app.readRows(2)
+	assert.NoError(t, err)
+	inID := records[1].id
+
+	badTime := base.Add(2 * time.Hour)
+	err = app.UpdateRecord(inID, &badTime, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestDeleteRecord(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assert.NoError(t, app.writeRow(clockInAction, "work"))
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, app.writeRow(clockOutAction, "work"))
+
+	records, err := app.readRows(2)
+	assert.NoError(t, err)
+	outID := records[0].id
+
+	assert.NoError(t, app.DeleteRecord(outID))
+
+	records, err = app.readRows(10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+}
+
+func TestDeleteRecordRejectsInvalidSequence(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assert.NoError(t, app.writeRow(clockInAction, "work"))
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, app.writeRow(clockOutAction, "work"))
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, app.writeRow(clockInAction, "work"))
+
+	records, err := app.readRows(3)
+	assert.NoError(t, err)
+	middleID := records[1].id
+
+	err = app.DeleteRecord(middleID)
+	assert.Error(t, err)
+}
+
+func TestDeleteRecordRejectsInvalidFirstRecord(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assert.NoError(t, app.writeRow(clockInAction, "work"))
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, app.writeRow(clockOutAction, "work"))
+
+	records, err := app.readRows(2)
+	assert.NoError(t, err)
+	inID := records[1].id
+
+	err = app.DeleteRecord(inID)
+	assert.Error(t, err)
+
+	records, err = app.readRows(10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+}
+
+func TestAmendRecord(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assert.NoError(t, app.writeRow(clockInAction, "work"))
+
+	newCategory := "personal"
+	assert.NoError(t, app.AmendRecord(nil, nil, &newCategory))
+
+	records, err := app.readRows(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "personal", records[0].category)
+}
@@ -0,0 +1,7 @@
+package main
+
+// systemIdleTime reports how long the system has seen no keyboard or mouse
+// input. It's a var rather than a plain function so tests can substitute a
+// fake; platformIdleTime (build-tagged per OS) holds the real
+// implementation.
+var systemIdleTime = platformIdleTime
@@ -0,0 +1,52 @@
+//go:build darwin
+
+package main
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <IOKit/IOKitLib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// platformIdleTime reads the HIDIdleTime property off the IOHIDSystem
+// service in IOKit's registry, which macOS maintains as nanoseconds since
+// the last keyboard or mouse event.
+func platformIdleTime() (time.Duration, error) {
+	var iter C.io_iterator_t
+	matching := C.IOServiceMatching(C.CString("IOHIDSystem"))
+	if kr := C.IOServiceGetMatchingServices(C.kIOMasterPortDefault, matching, &iter); kr != C.KERN_SUCCESS {
+		return 0, fmt.Errorf("error matching IOHIDSystem service")
+	}
+	defer C.IOObjectRelease(C.io_object_t(iter))
+
+	entry := C.IOIteratorNext(iter)
+	if entry == 0 {
+		return 0, fmt.Errorf("error finding IOHIDSystem service")
+	}
+	defer C.IOObjectRelease(entry)
+
+	var properties C.CFMutableDictionaryRef
+	if kr := C.IORegistryEntryCreateCFProperties(entry, &properties, C.kCFAllocatorDefault, 0); kr != C.KERN_SUCCESS {
+		return 0, fmt.Errorf("error reading IOHIDSystem properties")
+	}
+	defer C.CFRelease(C.CFTypeRef(properties))
+
+	key := C.CFStringCreateWithCString(C.kCFAllocatorDefault, C.CString("HIDIdleTime"), C.kCFStringEncodingUTF8)
+	defer C.CFRelease(C.CFTypeRef(key))
+
+	value := C.CFDictionaryGetValue(C.CFDictionaryRef(properties), unsafe.Pointer(key))
+	if value == nil {
+		return 0, fmt.Errorf("HIDIdleTime property not found")
+	}
+
+	var nanos C.int64_t
+	C.CFNumberGetValue(C.CFNumberRef(value), C.kCFNumberSInt64Type, unsafe.Pointer(&nanos))
+
+	return time.Duration(nanos), nil
+}
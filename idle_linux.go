@@ -0,0 +1,38 @@
+//go:build linux
+
+package main
+
+/*
+#cgo LDFLAGS: -lX11 -lXss
+#include <X11/Xlib.h>
+#include <X11/extensions/scrnsaver.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// platformIdleTime reports how long the X11 server has seen no input, via
+// the XScreenSaver extension. Wayland compositors that expose an X11
+// compatibility layer (XWayland) work through this too; there is no
+// portable native-Wayland idle query yet.
+func platformIdleTime() (time.Duration, error) {
+	display := C.XOpenDisplay(nil)
+	if display == nil {
+		return 0, fmt.Errorf("error opening X11 display (is DISPLAY set?)")
+	}
+	defer C.XCloseDisplay(display)
+
+	info := C.XScreenSaverAllocInfo()
+	defer C.XFree(unsafe.Pointer(info))
+
+	root := C.XDefaultRootWindow(display)
+	if C.XScreenSaverQueryInfo(display, root, info) == 0 {
+		return 0, fmt.Errorf("error querying X11 screen saver info")
+	}
+
+	return time.Duration(info.idle) * time.Millisecond, nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !windows
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// platformIdleTime is unimplemented on this platform; clock daemon refuses
+// to start rather than silently never auto clocking out.
+func platformIdleTime() (time.Duration, error) {
+	return 0, fmt.Errorf("idle detection is not supported on this platform")
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+var (
+	user32               = syscall.NewLazyDLL("user32.dll")
+	procGetLastInputInfo = user32.NewProc("GetLastInputInfo")
+	kernel32             = syscall.NewLazyDLL("kernel32.dll")
+	procGetTickCount     = kernel32.NewProc("GetTickCount")
+)
+
+// platformIdleTime reports time since the last keyboard or mouse input,
+// derived from GetLastInputInfo and GetTickCount, both measured in
+// milliseconds since system start.
+func platformIdleTime() (time.Duration, error) {
+	var info lastInputInfo
+	info.cbSize = uint32(unsafe.Sizeof(info))
+
+	ret, _, err := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info)))
+	if ret == 0 {
+		return 0, fmt.Errorf("error calling GetLastInputInfo: %v", err)
+	}
+
+	tick, _, _ := procGetTickCount.Call()
+
+	return time.Duration(uint32(tick)-info.dwTime) * time.Millisecond, nil
+}
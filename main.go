@@ -6,35 +6,46 @@ import (
 	"log"
 	"os"
 	"slices"
+	"strconv"
 	"text/tabwriter"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
 	"github.com/spf13/cobra"
 )
 
 type ClockApp struct {
-	DB   *sql.DB
-	Path string
+	Store Store
+	Path  string
 }
 
-func NewClockApp() (*ClockApp, error) {
-	path, err := ensureDbPath()
+// NewClockApp opens the store for the given backend ("" resolves via
+// resolveBackend) and wraps it in a ClockApp.
+func NewClockApp(backend string) (*ClockApp, error) {
+	store, err := openStore(resolveBackend(backend))
 	if err != nil {
 		return nil, err
 	}
-	db, err := sql.Open("sqlite3", path)
-	if err != nil {
-		return nil, fmt.Errorf("error opening database: %v", err)
-	}
-	err = ensureTable(db)
-	if err != nil {
+	if _, err := MigrateLegacyTimes(store); err != nil {
 		return nil, err
 	}
-	return &ClockApp{DB: db, Path: path}, nil
+	return &ClockApp{Store: store, Path: storePath(store)}, nil
 }
 
-func ensureDbPath() (string, error) {
+// storePath returns the on-disk path of a store, for display purposes.
+func storePath(store Store) string {
+	switch s := store.(type) {
+	case *sqliteStore:
+		return s.path
+	case *boltStore:
+		return s.path
+	default:
+		return ""
+	}
+}
+
+// ensureBackendPath returns the path to filename inside ~/.clock,
+// creating the directory if necessary.
+func ensureBackendPath(filename string) (string, error) {
 	homedir, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("error getting home directory: %v", err)
@@ -46,12 +57,17 @@ func ensureDbPath() (string, error) {
 			return "", fmt.Errorf("error creating directory: %v", err)
 		}
 	}
-	return homedir + "/.clock/clock.db", nil
+	return dir + "/" + filename, nil
 }
 
 func ensureTable(db *sql.DB) error {
-	sqlStmt := `create table if not exists records 
-    (id integer not null primary key, time text, action text, category text);`
+	sqlStmt := `create table if not exists records
+    (id integer not null primary key, time text, action text, category text);
+    create index if not exists idx_records_time on records (time);
+    create table if not exists heartbeats
+    (id integer not null primary key check (id = 1), time text not null);
+    create table if not exists targets
+    (category text not null primary key, daily_seconds integer not null);`
 	_, err := db.Exec(sqlStmt)
 	if err != nil {
 		return fmt.Errorf("error creating table: %v", err)
@@ -62,10 +78,22 @@ func ensureTable(db *sql.DB) error {
 type clockAction string
 
 const (
-	clockInAction  clockAction = "in"
-	clockOutAction clockAction = "out"
+	clockInAction   clockAction = "in"
+	clockOutAction  clockAction = "out"
+	pomoInAction    clockAction = "pomo_in"
+	pomoBreakAction clockAction = "pomo_break"
 )
 
+// isActiveAction reports whether action represents being "clocked in" in
+// some form -- a plain work session or a pomodoro work interval -- as
+// opposed to "clocked out" (a plain out or a pomodoro break). clockInOut,
+// checkAlternation, and Report all key off this instead of comparing
+// actions directly, so pomodoro sessions obey the same alternation rules
+// as ordinary clock in/out without duplicating the logic.
+func isActiveAction(action clockAction) bool {
+	return action == clockInAction || action == pomoInAction
+}
+
 type Record struct {
 	id       int
 	time     string
@@ -74,37 +102,12 @@ type Record struct {
 }
 
 func (app *ClockApp) readRows(n int) ([]Record, error) {
-	rows, err := app.DB.Query(
-		"select id, time, action, category from records order by id desc limit ?;",
-		n,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error getting last %d records: %v", n, err)
-	}
-	defer rows.Close()
-
-	var records []Record
-	for rows.Next() {
-		var record Record
-		err = rows.Scan(&record.id, &record.time, &record.action, &record.category)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning record: %v", err)
-		}
-		records = append(records, record)
-	}
-	return records, nil
+	return app.Store.ReadRows(n)
 }
 
 func (app *ClockApp) writeRow(action clockAction, category string) error {
-	_, err := app.DB.Exec(
-		"insert into records (time, action, category) values (datetime('now'), ?, ?);",
-		action,
-		category,
-	)
-	if err != nil {
-		return fmt.Errorf("error inserting record: %v", err)
-	}
-	return nil
+	_, err := app.Store.WriteRow(Record{action: action, category: category})
+	return err
 }
 
 func (app *ClockApp) clockInOut(action clockAction, category string) error {
@@ -113,21 +116,19 @@ func (app *ClockApp) clockInOut(action clockAction, category string) error {
 		return err
 	}
 	if len(states) == 0 {
-		switch action {
-		case clockInAction:
+		if isActiveAction(action) {
 			return app.writeRow(action, category)
-		case clockOutAction:
-			return fmt.Errorf("cannot clock out without clocking in first")
 		}
+		return fmt.Errorf("cannot clock out without clocking in first")
 	}
 	state := states[0]
-	if (action == clockInAction) && (state.action == clockInAction) {
+	if isActiveAction(action) && isActiveAction(state.action) {
 		return fmt.Errorf("already clocked in (%s @ %v)", state.category, state.time)
 	}
-	if (action == clockOutAction) && (state.action == clockOutAction) {
+	if !isActiveAction(action) && !isActiveAction(state.action) {
 		return fmt.Errorf("already clocked out (%s @ %v)", state.category, state.time)
 	}
-	if (action == clockOutAction) && (state.action == clockInAction) {
+	if !isActiveAction(action) && isActiveAction(state.action) {
 		if (category != "") && (state.category != category) {
 			return fmt.Errorf("cannot clock out of a different category (%s)", state.category)
 		}
@@ -138,7 +139,7 @@ func (app *ClockApp) clockInOut(action clockAction, category string) error {
 	return app.writeRow(action, category)
 }
 
-func (app *ClockApp) printTimeElapsed() error {
+func (app *ClockApp) printTimeElapsed(loc *time.Location) error {
 	records, err := app.readRows(2)
 	if err != nil {
 		return err
@@ -146,15 +147,16 @@ func (app *ClockApp) printTimeElapsed() error {
 	if len(records) < 2 {
 		return fmt.Errorf("not enough records to calculate time elapsed")
 	}
-	startTime, err := time.Parse("2006-01-02 15:04:05", records[1].time)
+	startTime, err := parseRecordTime(records[1].time)
 	if err != nil {
 		return fmt.Errorf("error parsing start time: %v", err)
 	}
-	endTime, err := time.Parse("2006-01-02 15:04:05", records[0].time)
+	endTime, err := parseRecordTime(records[0].time)
 	if err != nil {
 		return fmt.Errorf("error parsing end time: %v", err)
 	}
 	elapsed := endTime.Sub(startTime)
+	startTime, endTime = startTime.In(loc), endTime.In(loc)
 	if records[0].action == clockInAction {
 		fmt.Printf("Last clock in was from %v to %v (%v)\n", startTime, endTime, elapsed)
 	} else {
@@ -163,7 +165,7 @@ func (app *ClockApp) printTimeElapsed() error {
 	return nil
 }
 
-func (app *ClockApp) printLog(n int) error {
+func (app *ClockApp) printLog(n int, loc *time.Location) error {
 	records, err := app.readRows(n)
 	if err != nil {
 		return err
@@ -172,13 +174,17 @@ func (app *ClockApp) printLog(n int) error {
 	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
 	fmt.Fprintln(w, "ID\tAction\tCategory\tTime")
 	for _, record := range records {
+		recordTime, err := parseRecordTime(record.time)
+		if err != nil {
+			return err
+		}
 		fmt.Fprintf(
 			w,
 			"%d:\t%s\t%s\t%s\n",
 			record.id,
 			record.action,
 			record.category,
-			record.time,
+			recordTime.In(loc).Format(displayTimeLayout),
 		)
 	}
 	w.Flush()
@@ -198,10 +204,10 @@ func parseCategory(args []string) string {
 
 func main() {
 	log.SetFlags(0)
-	app, err := NewClockApp()
-	if err != nil {
-		log.Fatal(err)
-	}
+
+	var app *ClockApp
+	var backend string
+	var timezone string
 
 	var clockInCmd = &cobra.Command{
 		Use:   "in",
@@ -235,10 +241,13 @@ func main() {
 		Short: "Show the log of recent clock actions",
 		Long:  ``,
 		Run: func(cmd *cobra.Command, args []string) {
-			err := app.printLog(n)
+			loc, err := resolveLocation(timezone)
 			if err != nil {
 				log.Fatal(err)
 			}
+			if err := app.printLog(n, loc); err != nil {
+				log.Fatal(err)
+			}
 		},
 	}
 	clockLogCmd.Flags().
@@ -249,6 +258,10 @@ func main() {
 		Short: "Show the current status",
 		Long:  ``,
 		Run: func(cmd *cobra.Command, args []string) {
+			loc, err := resolveLocation(timezone)
+			if err != nil {
+				log.Fatal(err)
+			}
 			records, err := app.readRows(1)
 			if err != nil {
 				log.Fatal(err)
@@ -257,7 +270,7 @@ func main() {
 				log.Fatalln("No records found")
 			}
 			record := records[0]
-			startTime, err := time.Parse("2006-01-02 15:04:05", record.time)
+			startTime, err := parseRecordTime(record.time)
 			if err != nil {
 				log.Fatal(err)
 			}
@@ -267,14 +280,329 @@ func main() {
 				"Clock: %s @ %s from %s (%s)\n",
 				record.action,
 				record.category,
-				record.time,
+				startTime.In(loc).Format(displayTimeLayout),
 				elapsed,
 			)
+			if target, ok, err := app.targetForCategory(record.category); err != nil {
+				log.Fatal(err)
+			} else if ok {
+				progress, err := app.TargetProgressToday(target, loc)
+				if err != nil {
+					log.Fatal(err)
+				}
+				fmt.Printf("Target: %s\n", progress.ProgressBar())
+			}
 		},
 	}
 
-	var rootCmd = &cobra.Command{Use: "clock"}
-	rootCmd.AddCommand(clockInCmd, clockOutCmd, clockLogCmd, clockStatusCmd)
+	var (
+		reportFrom      string
+		reportTo        string
+		reportToday     bool
+		reportThisWeek  bool
+		reportThisMonth bool
+		reportCategory  string
+		reportFormat    string
+	)
+	var clockReportCmd = &cobra.Command{
+		Use:   "report",
+		Short: "Report aggregated time per category over a date range",
+		Long:  ``,
+		Run: func(cmd *cobra.Command, args []string) {
+			loc, err := resolveLocation(timezone)
+			if err != nil {
+				log.Fatal(err)
+			}
+			from, to, err := resolveReportRange(reportFrom, reportTo, reportToday, reportThisWeek, reportThisMonth, loc)
+			if err != nil {
+				log.Fatal(err)
+			}
+			report, err := app.Report(from, to, reportCategory)
+			if err != nil {
+				log.Fatal(err)
+			}
+			formatter, err := formatterFor(reportFormat)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := formatter.Format(os.Stdout, report); err != nil {
+				log.Fatal(err)
+			}
+			for _, total := range report.Totals {
+				target, ok, err := app.targetForCategory(total.Category)
+				if err != nil {
+					log.Fatal(err)
+				}
+				if !ok {
+					continue
+				}
+				progress := TargetProgress{Target: Target{Category: total.Category, Daily: target.Daily}, Elapsed: total.Duration}
+				fmt.Printf("%s target: %s\n", total.Category, progress.ProgressBar())
+			}
+		},
+	}
+	clockReportCmd.Flags().StringVar(&reportFrom, "from", "", "Start date (YYYY-MM-DD)")
+	clockReportCmd.Flags().StringVar(&reportTo, "to", "", "End date (YYYY-MM-DD)")
+	clockReportCmd.Flags().BoolVar(&reportToday, "today", false, "Report on today only")
+	clockReportCmd.Flags().BoolVar(&reportThisWeek, "this-week", false, "Report on the current week")
+	clockReportCmd.Flags().BoolVar(&reportThisMonth, "this-month", false, "Report on the current month")
+	clockReportCmd.Flags().StringVar(&reportCategory, "category", "", "Only report on this category")
+	clockReportCmd.Flags().StringVar(&reportFormat, "format", "table", "Output format: table, csv, json, markdown")
+
+	var (
+		editTime     string
+		editAction   string
+		editCategory string
+	)
+	var clockEditCmd = &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Edit a record's time, action, or category",
+		Long:  ``,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				log.Fatalf("invalid record id %q: %v", args[0], err)
+			}
+			loc, err := resolveLocation(timezone)
+			if err != nil {
+				log.Fatal(err)
+			}
+			newTime, action, category, err := parseEditFlags(editTime, editAction, editCategory, loc)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := app.UpdateRecord(id, newTime, action, category); err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+	clockEditCmd.Flags().StringVar(&editTime, "time", "", "New time (2006-01-02 15:04:05)")
+	clockEditCmd.Flags().StringVar(&editAction, "action", "", "New action (in or out)")
+	clockEditCmd.Flags().StringVar(&editCategory, "category", "", "New category")
+
+	var clockDeleteCmd = &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete a record",
+		Long:  ``,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				log.Fatalf("invalid record id %q: %v", args[0], err)
+			}
+			if err := app.DeleteRecord(id); err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+
+	var (
+		amendTime     string
+		amendAction   string
+		amendCategory string
+	)
+	var clockAmendCmd = &cobra.Command{
+		Use:   "amend",
+		Short: "Modify the most recent record",
+		Long:  ``,
+		Run: func(cmd *cobra.Command, args []string) {
+			loc, err := resolveLocation(timezone)
+			if err != nil {
+				log.Fatal(err)
+			}
+			newTime, action, category, err := parseEditFlags(amendTime, amendAction, amendCategory, loc)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := app.AmendRecord(newTime, action, category); err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+	clockAmendCmd.Flags().StringVar(&amendTime, "time", "", "New time (2006-01-02 15:04:05)")
+	clockAmendCmd.Flags().StringVar(&amendAction, "action", "", "New action (in or out)")
+	clockAmendCmd.Flags().StringVar(&amendCategory, "category", "", "New category")
+
+	var migrateFrom, migrateTo string
+	var clockMigrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy all records from one backend to another",
+		Long:  ``,
+		Run: func(cmd *cobra.Command, args []string) {
+			if migrateFrom == "" || migrateTo == "" {
+				log.Fatal("both --from and --to are required")
+			}
+			n, err := MigrateBackend(migrateFrom, migrateTo)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("migrated %d record(s) from %s to %s\n", n, migrateFrom, migrateTo)
+		},
+	}
+	clockMigrateCmd.Flags().StringVar(&migrateFrom, "from", "", "Source backend: sqlite or bolt")
+	clockMigrateCmd.Flags().StringVar(&migrateTo, "to", "", "Destination backend: sqlite or bolt")
+
+	var (
+		daemonIdle   string
+		daemonResume bool
+	)
+	var clockDaemonCmd = &cobra.Command{
+		Use:   "daemon",
+		Short: "Monitor idle time and auto clock out after a period of inactivity",
+		Long:  ``,
+		Run: func(cmd *cobra.Command, args []string) {
+			threshold, err := time.ParseDuration(daemonIdle)
+			if err != nil {
+				log.Fatalf("invalid --idle duration: %v", err)
+			}
+			if err := RunDaemon(app, DaemonOptions{IdleThreshold: threshold, Resume: daemonResume}); err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+	clockDaemonCmd.PersistentFlags().
+		StringVar(&daemonIdle, "idle", "15m", "Idle duration after which to auto clock out")
+	clockDaemonCmd.Flags().
+		BoolVar(&daemonResume, "resume", false, "Auto clock back in under the same category when activity resumes")
+
+	var clockDaemonStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show elapsed idle time and the daemon's next auto-action",
+		Long:  ``,
+		Run: func(cmd *cobra.Command, args []string) {
+			threshold, err := time.ParseDuration(daemonIdle)
+			if err != nil {
+				log.Fatalf("invalid --idle duration: %v", err)
+			}
+			status, err := GetDaemonStatus(app, threshold)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("Idle: %v (threshold %v)\n", status.Idle.Round(time.Second), status.IdleThreshold)
+			fmt.Printf("Next action: %s\n", status.NextAction)
+		},
+	}
+	clockDaemonCmd.AddCommand(clockDaemonStatusCmd)
+
+	var (
+		pomoWork      string
+		pomoBreak     string
+		pomoLongBreak string
+		pomoCycles    int
+		pomoCategory  string
+	)
+	var clockPomodoroCmd = &cobra.Command{
+		Use:   "pomodoro",
+		Short: "Run a pomodoro session of alternating work and break intervals",
+		Long:  ``,
+		Run: func(cmd *cobra.Command, args []string) {
+			work, err := time.ParseDuration(pomoWork)
+			if err != nil {
+				log.Fatalf("invalid --work duration: %v", err)
+			}
+			brk, err := time.ParseDuration(pomoBreak)
+			if err != nil {
+				log.Fatalf("invalid --break duration: %v", err)
+			}
+			longBreak, err := time.ParseDuration(pomoLongBreak)
+			if err != nil {
+				log.Fatalf("invalid --long-break duration: %v", err)
+			}
+			opts := PomodoroOptions{
+				Work: work, Break: brk, LongBreak: longBreak,
+				Cycles: pomoCycles, Category: pomoCategory,
+			}
+			if err := RunPomodoro(app, NewNotifier(), opts); err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+	clockPomodoroCmd.Flags().StringVar(&pomoWork, "work", "25m", "Work interval duration")
+	clockPomodoroCmd.Flags().StringVar(&pomoBreak, "break", "5m", "Short break duration")
+	clockPomodoroCmd.Flags().StringVar(&pomoLongBreak, "long-break", "15m", "Long break duration, taken after the last cycle")
+	clockPomodoroCmd.Flags().IntVar(&pomoCycles, "cycles", 4, "Number of work/break cycles")
+	clockPomodoroCmd.Flags().StringVar(&pomoCategory, "category", "", "Category to record work intervals under")
+
+	var (
+		targetCategory string
+		targetDaily    string
+	)
+	var clockTargetCmd = &cobra.Command{
+		Use:   "target",
+		Short: "Set or show daily time targets per category",
+		Long:  ``,
+		Run: func(cmd *cobra.Command, args []string) {
+			loc, err := resolveLocation(timezone)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if targetDaily != "" {
+				daily, err := time.ParseDuration(targetDaily)
+				if err != nil {
+					log.Fatalf("invalid --daily duration: %v", err)
+				}
+				if err := app.Store.SetTarget(Target{Category: targetCategory, Daily: daily}); err != nil {
+					log.Fatal(err)
+				}
+			}
+
+			targets, err := app.Store.ListTargets()
+			if err != nil {
+				log.Fatal(err)
+			}
+			if targetCategory != "" {
+				var filtered []Target
+				for _, target := range targets {
+					if target.Category == targetCategory {
+						filtered = append(filtered, target)
+					}
+				}
+				targets = filtered
+			}
+			if len(targets) == 0 {
+				fmt.Println("No targets configured")
+				return
+			}
+			for _, target := range targets {
+				progress, err := app.TargetProgressToday(target, loc)
+				if err != nil {
+					log.Fatal(err)
+				}
+				label := target.Category
+				if label == "" {
+					label = "(all categories)"
+				}
+				fmt.Printf("%s: %s\n", label, progress.ProgressBar())
+			}
+		},
+	}
+	clockTargetCmd.Flags().StringVar(&targetCategory, "category", "", "Category to target (default: all categories combined)")
+	clockTargetCmd.Flags().StringVar(&targetDaily, "daily", "", "Set the daily target duration, e.g. 8h")
+
+	var rootCmd = &cobra.Command{
+		Use: "clock",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd == clockMigrateCmd {
+				return nil
+			}
+			a, err := NewClockApp(backend)
+			if err != nil {
+				return err
+			}
+			app = a
+			return nil
+		},
+	}
+	rootCmd.PersistentFlags().
+		StringVar(&backend, "backend", "", "Storage backend: sqlite or bolt (default: $CLOCK_BACKEND or sqlite)")
+	rootCmd.PersistentFlags().
+		StringVar(&timezone, "timezone", "", "Timezone used to display times, e.g. America/New_York (default: $CLOCK_TZ or local)")
+	rootCmd.AddCommand(
+		clockInCmd, clockOutCmd, clockLogCmd, clockStatusCmd, clockReportCmd,
+		clockEditCmd, clockDeleteCmd, clockAmendCmd, clockMigrateCmd, clockDaemonCmd,
+		clockPomodoroCmd, clockTargetCmd,
+	)
 	rootCmd.CompletionOptions.HiddenDefaultCmd = true
 	rootCmd.Execute()
 }
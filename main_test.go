@@ -8,6 +8,7 @@ import (
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
+	"go.etcd.io/bbolt"
 )
 
 func setupTestDB(t *testing.T) (*ClockApp, func()) {
@@ -24,9 +25,46 @@ func setupTestDB(t *testing.T) (*ClockApp, func()) {
 	if err != nil {
 		t.Fatalf("Error creating table: %v", err)
 	}
-	app := &ClockApp{DB: db, Path: dbPath}
+	store := &sqliteStore{db: db, path: dbPath}
+	app := &ClockApp{Store: store, Path: dbPath}
 	return app, func() {
-		db.Close()
+		store.Close()
+		os.RemoveAll(tempDir)
+	}
+}
+
+// setupTestBoltDB mirrors setupTestDB but constructs a boltStore over a
+// temp file, so bolt-backed tests don't have to go through
+// openBoltStore's ~/.clock path resolution and lock file.
+func setupTestBoltDB(t *testing.T) (*ClockApp, func()) {
+	tempDir, err := os.MkdirTemp("", "clock_test_bolt")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %v", err)
+	}
+	dbPath := tempDir + "/test.bolt"
+	lockFile, err := acquireLockFile(dbPath + ".lock")
+	if err != nil {
+		t.Fatalf("Error acquiring lock file: %v", err)
+	}
+	db, err := bbolt.Open(dbPath, 0600, &bbolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("Error opening bolt database: %v", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range []string{boltRecordsBucket, boltCategoryBucket, boltIDBucket, boltHeartbeatBucket, boltTargetsBucket} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error initializing bolt buckets: %v", err)
+	}
+	store := &boltStore{db: db, path: dbPath, lockFile: lockFile}
+	app := &ClockApp{Store: store, Path: dbPath}
+	return app, func() {
+		store.Close()
 		os.RemoveAll(tempDir)
 	}
 }
@@ -79,8 +117,10 @@ func TestReadRows(t *testing.T) {
 	assert.Equal(t, "work", records[1].category)
 
 	// Check time difference
-	startTime, _ := time.Parse("2006-01-02 15:04:05", records[1].time)
-	endTime, _ := time.Parse("2006-01-02 15:04:05", records[0].time)
+	startTime, err := parseRecordTime(records[1].time)
+	assert.NoError(t, err)
+	endTime, err := parseRecordTime(records[0].time)
+	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, endTime.Sub(startTime), time.Second)
 }
 
@@ -0,0 +1,57 @@
+package main
+
+import "fmt"
+
+// MigrateBackend opens the from and to backends and copies every record
+// from one to the other, returning the number of records copied.
+func MigrateBackend(from, to string) (int, error) {
+	src, err := openStore(from)
+	if err != nil {
+		return 0, fmt.Errorf("error opening source backend %q: %v", from, err)
+	}
+	defer src.Close()
+
+	dst, err := openStore(to)
+	if err != nil {
+		return 0, fmt.Errorf("error opening destination backend %q: %v", to, err)
+	}
+	defer dst.Close()
+
+	return MigrateStores(src, dst)
+}
+
+// MigrateStores copies every record, the heartbeat, and all targets from
+// src to dst, returning the number of records copied. Records preserve
+// time, action, and category but let dst assign its own ids.
+func MigrateStores(src, dst Store) (int, error) {
+	records, err := src.RangeByTime(farPast, farFuture, "")
+	if err != nil {
+		return 0, fmt.Errorf("error reading source records: %v", err)
+	}
+	for i, record := range records {
+		record.id = 0
+		if _, err := dst.WriteRow(record); err != nil {
+			return i, fmt.Errorf("error writing record %d: %v", i, err)
+		}
+	}
+
+	if heartbeat, ok, err := src.GetHeartbeat(); err != nil {
+		return len(records), fmt.Errorf("error reading source heartbeat: %v", err)
+	} else if ok {
+		if err := dst.SetHeartbeat(heartbeat); err != nil {
+			return len(records), fmt.Errorf("error writing heartbeat: %v", err)
+		}
+	}
+
+	targets, err := src.ListTargets()
+	if err != nil {
+		return len(records), fmt.Errorf("error reading source targets: %v", err)
+	}
+	for _, target := range targets {
+		if err := dst.SetTarget(target); err != nil {
+			return len(records), fmt.Errorf("error writing target %q: %v", target.Category, err)
+		}
+	}
+
+	return len(records), nil
+}
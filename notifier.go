@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// Notifier sends a desktop notification for pomodoro transitions. Notify logs its own failures rather than returning them, so
+// a missing or broken notification backend never interrupts a session.
+type Notifier interface {
+	Notify(title, message string)
+}
+
+// stubNotifier discards notifications; it's the fallback wherever a
+// platform-specific backend isn't available.
+type stubNotifier struct{}
+
+func (stubNotifier) Notify(title, message string) {}
+
+// commandNotifier sends a notification by shelling out to a CLI tool
+// (notify-send on Linux, osascript on macOS).
+type commandNotifier struct {
+	bin  string
+	args func(title, message string) []string
+}
+
+func (n commandNotifier) Notify(title, message string) {
+	if err := exec.Command(n.bin, n.args(title, message)...).Run(); err != nil {
+		fmt.Printf("notification failed: %v\n", err)
+	}
+}
+
+// NewNotifier returns the best available notifier for the current
+// platform, falling back to a stub if none is found. platformNotifier is
+// build-tagged per OS.
+var NewNotifier = platformNotifier
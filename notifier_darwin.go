@@ -0,0 +1,23 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// platformNotifier sends notifications via osascript's "display
+// notification" command.
+func platformNotifier() Notifier {
+	if _, err := exec.LookPath("osascript"); err != nil {
+		return stubNotifier{}
+	}
+	return commandNotifier{
+		bin: "osascript",
+		args: func(title, message string) []string {
+			script := fmt.Sprintf("display notification %q with title %q", message, title)
+			return []string{"-e", script}
+		},
+	}
+}
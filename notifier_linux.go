@@ -0,0 +1,17 @@
+//go:build linux
+
+package main
+
+import "os/exec"
+
+// platformNotifier talks to libnotify via the notify-send CLI tool,
+// avoiding a direct D-Bus binding dependency.
+func platformNotifier() Notifier {
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return stubNotifier{}
+	}
+	return commandNotifier{
+		bin:  "notify-send",
+		args: func(title, message string) []string { return []string{title, message} },
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package main
+
+// platformNotifier has no desktop-notification backend on this platform.
+func platformNotifier() Notifier {
+	return stubNotifier{}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// PomodoroOptions configures a single `clock pomodoro` run.
+type PomodoroOptions struct {
+	Work      time.Duration
+	Break     time.Duration
+	LongBreak time.Duration
+	Cycles    int
+	Category  string
+}
+
+// RunPomodoro drives Cycles work/break intervals, using a long break after
+// the final cycle instead of a short one, and notifies at each transition.
+// Work intervals are recorded as pomoInAction and breaks as
+// pomoBreakAction, so the sequence is just an alternating active/inactive
+// sequence under different names, passing through clockInOut (and so the
+// ordinary alternation invariant) like any other clock action. The session
+// ends mid-break rather than with an explicit clock-out, since a break is
+// already an inactive state.
+func RunPomodoro(app *ClockApp, notifier Notifier, opts PomodoroOptions) error {
+	if opts.Cycles <= 0 {
+		opts.Cycles = 4
+	}
+
+	for cycle := 1; cycle <= opts.Cycles; cycle++ {
+		if err := app.clockInOut(pomoInAction, opts.Category); err != nil {
+			return fmt.Errorf("error starting work interval %d: %v", cycle, err)
+		}
+		notifier.Notify("Pomodoro", fmt.Sprintf("Work interval %d/%d started (%v)", cycle, opts.Cycles, opts.Work))
+		time.Sleep(opts.Work)
+
+		breakDuration, label := opts.Break, "Short break"
+		if cycle == opts.Cycles {
+			breakDuration, label = opts.LongBreak, "Long break"
+		}
+		if err := app.clockInOut(pomoBreakAction, opts.Category); err != nil {
+			return fmt.Errorf("error starting break %d: %v", cycle, err)
+		}
+		notifier.Notify("Pomodoro", fmt.Sprintf("%s started (%v)", label, breakDuration))
+		time.Sleep(breakDuration)
+	}
+
+	notifier.Notify("Pomodoro", "Session complete")
+	return nil
+}
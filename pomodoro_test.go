@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNotifier struct {
+	messages []string
+}
+
+func (n *fakeNotifier) Notify(title, message string) {
+	n.messages = append(n.messages, title+": "+message)
+}
+
+func TestRunPomodoroAlternatesActions(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	notifier := &fakeNotifier{}
+	opts := PomodoroOptions{
+		Work: time.Millisecond, Break: time.Millisecond, LongBreak: 2 * time.Millisecond,
+		Cycles: 2, Category: "work",
+	}
+	assert.NoError(t, RunPomodoro(app, notifier, opts))
+
+	records, err := app.readRows(10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 4)
+	// readRows returns newest first.
+	wantActions := []clockAction{pomoBreakAction, pomoInAction, pomoBreakAction, pomoInAction}
+	for i, record := range records {
+		assert.Equal(t, wantActions[i], record.action)
+		assert.Equal(t, "work", record.category)
+	}
+	assert.Contains(t, notifier.messages[len(notifier.messages)-1], "Session complete")
+}
+
+func TestRunPomodoroDefaultsCycles(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	opts := PomodoroOptions{Work: time.Millisecond, Break: time.Millisecond, LongBreak: time.Millisecond}
+	assert.NoError(t, RunPomodoro(app, &fakeNotifier{}, opts))
+
+	records, err := app.readRows(10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 8)
+}
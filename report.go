@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// CategoryTotal is the time accumulated for a single category within a
+// report's date range.
+type CategoryTotal struct {
+	Category string
+	Duration time.Duration
+}
+
+// Report is the result of aggregating records between From and To.
+type Report struct {
+	From   time.Time
+	To     time.Time
+	Totals []CategoryTotal
+}
+
+// Report aggregates clocked time per category between from and to
+// (inclusive), pairing active/inactive records (clock in/out, or a
+// pomodoro work interval/break) in chronological order via isActiveAction.
+// A session already open when the window starts (clocked in before from)
+// has its start clamped to from; a session still open when the window
+// ends is counted up to time.Now() (clamped to to). If category is
+// non-empty, only that category is included.
+func (app *ClockApp) Report(from, to time.Time, category string) (*Report, error) {
+	records, err := app.Store.RangeByTime(from, to, category)
+	if err != nil {
+		return nil, fmt.Errorf("error querying records for report: %v", err)
+	}
+
+	totals := map[string]time.Duration{}
+	openIn, err := app.openSessionBefore(from, category)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		record := records[i]
+		if isActiveAction(record.action) {
+			openIn = &record
+			continue
+		}
+		if openIn == nil {
+			continue
+		}
+		startTime, err := parseRecordTime(openIn.time)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing start time: %v", err)
+		}
+		if startTime.Before(from) {
+			startTime = from
+		}
+		endTime, err := parseRecordTime(record.time)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing end time: %v", err)
+		}
+		totals[openIn.category] += endTime.Sub(startTime)
+		openIn = nil
+	}
+
+	if openIn != nil {
+		startTime, err := parseRecordTime(openIn.time)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing start time: %v", err)
+		}
+		if startTime.Before(from) {
+			startTime = from
+		}
+		end := to
+		if now := time.Now(); now.Before(end) {
+			end = now
+		}
+		if end.After(startTime) {
+			totals[openIn.category] += end.Sub(startTime)
+		}
+	}
+
+	report := &Report{From: from, To: to}
+	for cat, dur := range totals {
+		report.Totals = append(report.Totals, CategoryTotal{Category: cat, Duration: dur})
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		return report.Totals[i].Category < report.Totals[j].Category
+	})
+	return report, nil
+}
+
+// openSessionBefore looks for a session that was already active at from,
+// i.e. one whose clock-in (or pomodoro work start) falls before the
+// report window and so isn't returned by RangeByTime(from, to, ...). At
+// most one category can be active at a time (clockInOut enforces a
+// single global session), so this checks the most recent record at or
+// before from regardless of category, then filters the result against
+// the requested category. It returns nil if nothing was open at from.
+func (app *ClockApp) openSessionBefore(from time.Time, category string) (*Record, error) {
+	records, err := app.Store.RangeByTime(farPast, from, "")
+	if err != nil {
+		return nil, fmt.Errorf("error checking for a session open before the report range: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	last := records[len(records)-1]
+	if !isActiveAction(last.action) {
+		return nil, nil
+	}
+	if category != "" && last.category != category {
+		return nil, nil
+	}
+	return &last, nil
+}
+
+// ReportFormatter renders a Report to w in a particular output format.
+type ReportFormatter interface {
+	Format(w io.Writer, report *Report) error
+}
+
+func formatterFor(format string) (ReportFormatter, error) {
+	switch format {
+	case "", "table":
+		return tableReportFormatter{}, nil
+	case "csv":
+		return csvReportFormatter{}, nil
+	case "json":
+		return jsonReportFormatter{}, nil
+	case "markdown":
+		return markdownReportFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
+
+type tableReportFormatter struct{}
+
+func (tableReportFormatter) Format(w io.Writer, report *Report) error {
+	tw := tabwriter.NewWriter(w, 1, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "Category\tTotal")
+	for _, total := range report.Totals {
+		fmt.Fprintf(tw, "%s\t%s\n", total.Category, formatDuration(total.Duration))
+	}
+	return tw.Flush()
+}
+
+type csvReportFormatter struct{}
+
+func (csvReportFormatter) Format(w io.Writer, report *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"category", "duration"}); err != nil {
+		return err
+	}
+	for _, total := range report.Totals {
+		err := cw.Write([]string{total.Category, strconv.FormatFloat(total.Duration.Hours(), 'f', 2, 64)})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type jsonReportFormatter struct{}
+
+func (jsonReportFormatter) Format(w io.Writer, report *Report) error {
+	type entry struct {
+		Category string  `json:"category"`
+		Hours    float64 `json:"hours"`
+	}
+	out := struct {
+		From  time.Time `json:"from"`
+		To    time.Time `json:"to"`
+		Total []entry   `json:"totals"`
+	}{From: report.From, To: report.To}
+	for _, total := range report.Totals {
+		out.Total = append(out.Total, entry{Category: total.Category, Hours: total.Duration.Hours()})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+type markdownReportFormatter struct{}
+
+func (markdownReportFormatter) Format(w io.Writer, report *Report) error {
+	fmt.Fprintf(w, "# Report: %s to %s\n\n", report.From.Format("2006-01-02"), report.To.Format("2006-01-02"))
+	fmt.Fprintln(w, "| Category | Total |")
+	fmt.Fprintln(w, "| --- | --- |")
+	for _, total := range report.Totals {
+		fmt.Fprintf(w, "| %s | %s |\n", total.Category, formatDuration(total.Duration))
+	}
+	return nil
+}
+
+// resolveReportRange turns the report command's flags into a concrete
+// [from, to] window, resolving today/this-week/this-month and bare
+// --from/--to dates against loc (the configured display timezone, not
+// necessarily the host's). today/thisWeek/thisMonth take precedence over
+// explicit from/to strings when set.
+func resolveReportRange(from, to string, today, thisWeek, thisMonth bool, loc *time.Location) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
+	switch {
+	case today:
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	case thisWeek:
+		weekday := int(now.Weekday())
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -weekday)
+		return start, start.AddDate(0, 0, 7).Add(-time.Nanosecond), nil
+	case thisMonth:
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 1, 0).Add(-time.Nanosecond), nil
+	}
+
+	var fromTime, toTime time.Time
+	var err error
+	if from != "" {
+		fromTime, err = time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("error parsing --from: %v", err)
+		}
+	} else {
+		fromTime = time.Time{}
+	}
+	if to != "" {
+		toTime, err = time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("error parsing --to: %v", err)
+		}
+		toTime = toTime.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	} else {
+		toTime = now
+	}
+	return fromTime, toTime, nil
+}
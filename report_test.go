@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func insertRecordAt(t *testing.T, app *ClockApp, when time.Time, action clockAction, category string) {
+	t.Helper()
+	_, err := app.Store.WriteRow(Record{
+		time:     when.UTC().Format(time.RFC3339),
+		action:   action,
+		category: category,
+	})
+	assert.NoError(t, err)
+}
+
+func TestReportClosedSessions(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertRecordAt(t, app, base, clockInAction, "work")
+	insertRecordAt(t, app, base.Add(2*time.Hour), clockOutAction, "work")
+	insertRecordAt(t, app, base.Add(3*time.Hour), clockInAction, "personal")
+	insertRecordAt(t, app, base.Add(3*time.Hour+30*time.Minute), clockOutAction, "personal")
+
+	report, err := app.Report(base.Add(-time.Hour), base.Add(4*time.Hour), "")
+	assert.NoError(t, err)
+	assert.Len(t, report.Totals, 2)
+	assert.Equal(t, "personal", report.Totals[0].Category)
+	assert.Equal(t, 30*time.Minute, report.Totals[0].Duration)
+	assert.Equal(t, "work", report.Totals[1].Category)
+	assert.Equal(t, 2*time.Hour, report.Totals[1].Duration)
+}
+
+func TestReportOpenSession(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	start := time.Now().Add(-time.Hour)
+	insertRecordAt(t, app, start, clockInAction, "work")
+
+	report, err := app.Report(start.Add(-time.Hour), start.Add(24*time.Hour), "")
+	assert.NoError(t, err)
+	assert.Len(t, report.Totals, 1)
+	assert.Equal(t, "work", report.Totals[0].Category)
+	assert.InDelta(t, time.Hour.Minutes(), report.Totals[0].Duration.Minutes(), 1)
+}
+
+func TestReportCrossDaySession(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	start := time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 2, 1, 0, 0, 0, time.UTC)
+	insertRecordAt(t, app, start, clockInAction, "work")
+	insertRecordAt(t, app, end, clockOutAction, "work")
+
+	report, err := app.Report(start.Add(-time.Hour), end.Add(time.Hour), "")
+	assert.NoError(t, err)
+	assert.Len(t, report.Totals, 1)
+	assert.Equal(t, 2*time.Hour, report.Totals[0].Duration)
+}
+
+func TestReportSessionOpenBeforeWindow(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	start := time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 2, 1, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	insertRecordAt(t, app, start, clockInAction, "work")
+	insertRecordAt(t, app, end, clockOutAction, "work")
+
+	report, err := app.Report(from, end.Add(23*time.Hour+59*time.Minute), "")
+	assert.NoError(t, err)
+	assert.Len(t, report.Totals, 1)
+	assert.Equal(t, "work", report.Totals[0].Category)
+	assert.Equal(t, time.Hour, report.Totals[0].Duration)
+}
+
+func TestReportEmptyRange(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertRecordAt(t, app, base, clockInAction, "work")
+	insertRecordAt(t, app, base.Add(time.Hour), clockOutAction, "work")
+
+	report, err := app.Report(base.Add(24*time.Hour), base.Add(48*time.Hour), "")
+	assert.NoError(t, err)
+	assert.Len(t, report.Totals, 0)
+}
+
+func TestReportCategoryFilter(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertRecordAt(t, app, base, clockInAction, "work")
+	insertRecordAt(t, app, base.Add(time.Hour), clockOutAction, "work")
+	insertRecordAt(t, app, base.Add(2*time.Hour), clockInAction, "personal")
+	insertRecordAt(t, app, base.Add(3*time.Hour), clockOutAction, "personal")
+
+	report, err := app.Report(base.Add(-time.Hour), base.Add(4*time.Hour), "work")
+	assert.NoError(t, err)
+	assert.Len(t, report.Totals, 1)
+	assert.Equal(t, "work", report.Totals[0].Category)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Store persists clock records independently of the underlying storage
+// engine. A Store is not safe for concurrent use by multiple processes;
+// callers are expected to serialize access (see the bolt store's lock
+// file for how that's enforced there).
+type Store interface {
+	// ReadRows returns the n most recent records, newest first.
+	ReadRows(n int) ([]Record, error)
+	// WriteRow inserts a new record and returns its id. A blank
+	// record.time means "now".
+	WriteRow(record Record) (int, error)
+	// UpdateRow overwrites the record matching record.id.
+	UpdateRow(record Record) error
+	// DeleteRow removes the record with the given id.
+	DeleteRow(id int) error
+	// RangeByTime returns records with time in [from, to], ascending by
+	// time, optionally filtered to a single category (all categories if
+	// category is "").
+	RangeByTime(from, to time.Time, category string) ([]Record, error)
+	// GetHeartbeat returns the last activity time recorded by the daemon,
+	// or ok=false if none has been recorded yet.
+	GetHeartbeat() (t time.Time, ok bool, err error)
+	// SetHeartbeat records t as the daemon's last-seen activity time.
+	SetHeartbeat(t time.Time) error
+	// GetTarget returns the daily target for category ("" for the
+	// all-categories target), or ok=false if none is set.
+	GetTarget(category string) (target Target, ok bool, err error)
+	// SetTarget creates or updates the daily target for a category.
+	SetTarget(target Target) error
+	// ListTargets returns every configured target.
+	ListTargets() ([]Target, error)
+	// DeleteTarget removes the target for category, if any.
+	DeleteTarget(category string) error
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+const (
+	backendSQLite = "sqlite"
+	backendBolt   = "bolt"
+)
+
+// resolveBackend picks the storage backend to use: an explicit flag wins,
+// then CLOCK_BACKEND, then the sqlite default.
+func resolveBackend(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if env := os.Getenv("CLOCK_BACKEND"); env != "" {
+		return env
+	}
+	return backendSQLite
+}
+
+// openStore opens the store for the named backend, creating it if
+// necessary.
+func openStore(backend string) (Store, error) {
+	switch backend {
+	case backendSQLite:
+		return openSQLiteStore()
+	case backendBolt:
+		return openBoltStore()
+	default:
+		return nil, fmt.Errorf("unknown backend %q (want %q or %q)", backend, backendSQLite, backendBolt)
+	}
+}
+
+// storeNeighbors returns the records immediately before and after id in
+// time order, if they exist. It is implemented in terms of the Store
+// interface so it works the same regardless of backend.
+func storeNeighbors(store Store, id int) (prev *Record, next *Record, err error) {
+	all, err := store.RangeByTime(farPast, farFuture, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error scanning records: %v", err)
+	}
+	for i, record := range all {
+		if record.id != id {
+			continue
+		}
+		if i > 0 {
+			p := all[i-1]
+			prev = &p
+		}
+		if i < len(all)-1 {
+			n := all[i+1]
+			next = &n
+		}
+		return prev, next, nil
+	}
+	return nil, nil, fmt.Errorf("no record with id %d", id)
+}
+
+// farPast and farFuture bound RangeByTime scans that want every record,
+// regardless of when it was written.
+var (
+	farPast   = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	farFuture = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// allCategoriesTargetKey is the storage key for the target that applies
+// across all categories combined (an empty Target.Category), since some
+// backends (bolt) reject an empty key outright.
+const allCategoriesTargetKey = "*"
+
+func targetStoreKey(category string) string {
+	if category == "" {
+		return allCategoriesTargetKey
+	}
+	return category
+}
+
+func targetCategoryFromKey(key string) string {
+	if key == allCategoriesTargetKey {
+		return ""
+	}
+	return key
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+const (
+	boltRecordsBucket   = "records"
+	boltCategoryBucket  = "by_category"
+	boltIDBucket        = "by_id"
+	boltHeartbeatBucket = "heartbeats"
+	boltTargetsBucket   = "targets"
+)
+
+// boltHeartbeatKey is the single key the heartbeats bucket ever holds;
+// there is only ever one daemon heartbeat per store.
+var boltHeartbeatKey = []byte("last")
+
+// boltStore is the Store implementation backed by a bbolt key-value
+// file, selected via CLOCK_BACKEND=bolt or --backend=bolt. Records are
+// keyed by RFC3339 timestamp (plus id, to disambiguate same-instant
+// writes) so RangeByTime is a cheap cursor scan, with a secondary bucket
+// indexing the same keys by category.
+type boltStore struct {
+	db       *bbolt.DB
+	path     string
+	lockFile *os.File
+}
+
+// boltRecordDTO is the JSON representation stored in the records bucket;
+// Record's fields are unexported so they can't be marshaled directly.
+type boltRecordDTO struct {
+	ID       int         `json:"id"`
+	Time     string      `json:"time"`
+	Action   clockAction `json:"action"`
+	Category string      `json:"category"`
+}
+
+func openBoltStore() (*boltStore, error) {
+	path, err := ensureBackendPath("clock.bolt")
+	if err != nil {
+		return nil, err
+	}
+	lockFile, err := acquireLockFile(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		releaseLockFile(lockFile)
+		return nil, fmt.Errorf("error opening bolt database: %v", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range []string{boltRecordsBucket, boltCategoryBucket, boltIDBucket, boltHeartbeatBucket, boltTargetsBucket} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		releaseLockFile(lockFile)
+		return nil, fmt.Errorf("error initializing bolt buckets: %v", err)
+	}
+	return &boltStore{db: db, path: path, lockFile: lockFile}, nil
+}
+
+// acquireLockFile creates an exclusive lock file so two CLI invocations
+// against the same bolt store don't race each other. bbolt already flocks
+// its own file, but this gives a friendlier error up front.
+func acquireLockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil, fmt.Errorf("clock database is locked (remove %s if no other clock process is running)", path)
+		}
+		return nil, fmt.Errorf("error creating lock file: %v", err)
+	}
+	fmt.Fprintf(f, "%d\n", os.Getpid())
+	return f, nil
+}
+
+func releaseLockFile(f *os.File) {
+	name := f.Name()
+	f.Close()
+	os.Remove(name)
+}
+
+// recordKey builds the records-bucket key for a record: its RFC3339 time
+// (so a lexicographic cursor scan is a time-ordered scan) followed by its
+// id, to disambiguate writes that land on the same instant.
+func recordKey(timeStr string, id int) []byte {
+	return []byte(fmt.Sprintf("%s|%020d", timeStr, id))
+}
+
+func idKey(id int) []byte {
+	return []byte(fmt.Sprintf("%020d", id))
+}
+
+// categoryKeyPrefix returns the boltCategoryBucket key prefix for
+// category: a 4-byte big-endian length followed by the category's bytes.
+// Length-prefixing (rather than a plain "category|" separator) keeps one
+// category's keys from ever compare-falling inside another's scan range,
+// even if a category name itself contains "|".
+func categoryKeyPrefix(category string) []byte {
+	prefix := make([]byte, 4+len(category))
+	binary.BigEndian.PutUint32(prefix, uint32(len(category)))
+	copy(prefix[4:], category)
+	return prefix
+}
+
+// categoryKey builds the full boltCategoryBucket key for a record: its
+// category prefix followed by its records-bucket key.
+func categoryKey(category string, recordKey []byte) []byte {
+	return append(categoryKeyPrefix(category), recordKey...)
+}
+
+func recordToDTO(r Record) boltRecordDTO {
+	return boltRecordDTO{ID: r.id, Time: r.time, Action: r.action, Category: r.category}
+}
+
+func dtoToRecord(d boltRecordDTO) Record {
+	return Record{id: d.ID, time: d.Time, action: d.Action, category: d.Category}
+}
+
+func (s *boltStore) ReadRows(n int) ([]Record, error) {
+	var out []Record
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket([]byte(boltRecordsBucket)).Cursor()
+		for k, v := c.Last(); k != nil && len(out) < n; k, v = c.Prev() {
+			var dto boltRecordDTO
+			if err := json.Unmarshal(v, &dto); err != nil {
+				return fmt.Errorf("error decoding record: %v", err)
+			}
+			out = append(out, dtoToRecord(dto))
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (s *boltStore) WriteRow(record Record) (int, error) {
+	if record.time == "" {
+		record.time = time.Now().UTC().Format(time.RFC3339)
+	}
+	var id int
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		seq, err := tx.Bucket([]byte(boltRecordsBucket)).NextSequence()
+		if err != nil {
+			return fmt.Errorf("error allocating record id: %v", err)
+		}
+		record.id = int(seq)
+		id = record.id
+		return putRecordTx(tx, record)
+	})
+	return id, err
+}
+
+func (s *boltStore) UpdateRow(record Record) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		if err := deleteRecordTx(tx, record.id); err != nil {
+			return err
+		}
+		return putRecordTx(tx, record)
+	})
+}
+
+func (s *boltStore) DeleteRow(id int) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return deleteRecordTx(tx, id)
+	})
+}
+
+// RangeByTime scans the records bucket directly when no category filter
+// is given. With a category filter it instead seeks through
+// boltCategoryBucket, whose keys are categoryKey(category, recordKey), so
+// the scan only visits that category's records rather than every record
+// in range.
+func (s *boltStore) RangeByTime(from, to time.Time, category string) ([]Record, error) {
+	if category == "" {
+		return s.rangeByTimeAll(from, to)
+	}
+	return s.rangeByTimeCategory(from, to, category)
+}
+
+func (s *boltStore) rangeByTimeAll(from, to time.Time) ([]Record, error) {
+	var out []Record
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket([]byte(boltRecordsBucket)).Cursor()
+		min := []byte(from.UTC().Format(time.RFC3339))
+		max := append([]byte(to.UTC().Format(time.RFC3339)), 0xff)
+		for k, v := c.Seek(min); k != nil && bytes.Compare(k, max) <= 0; k, v = c.Next() {
+			var dto boltRecordDTO
+			if err := json.Unmarshal(v, &dto); err != nil {
+				return fmt.Errorf("error decoding record: %v", err)
+			}
+			out = append(out, dtoToRecord(dto))
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (s *boltStore) rangeByTimeCategory(from, to time.Time, category string) ([]Record, error) {
+	var out []Record
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		records := tx.Bucket([]byte(boltRecordsBucket))
+		c := tx.Bucket([]byte(boltCategoryBucket)).Cursor()
+		prefix := categoryKeyPrefix(category)
+		min := append(append([]byte{}, prefix...), []byte(from.UTC().Format(time.RFC3339))...)
+		max := append(append([]byte{}, prefix...), append([]byte(to.UTC().Format(time.RFC3339)), 0xff)...)
+		for k, v := c.Seek(min); k != nil && bytes.Compare(k, max) <= 0; k, v = c.Next() {
+			raw := records.Get(v)
+			if raw == nil {
+				continue
+			}
+			var dto boltRecordDTO
+			if err := json.Unmarshal(raw, &dto); err != nil {
+				return fmt.Errorf("error decoding record: %v", err)
+			}
+			out = append(out, dtoToRecord(dto))
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (s *boltStore) GetHeartbeat() (time.Time, bool, error) {
+	var timeStr string
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		if v := tx.Bucket([]byte(boltHeartbeatBucket)).Get(boltHeartbeatKey); v != nil {
+			timeStr = string(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if timeStr == "" {
+		return time.Time{}, false, nil
+	}
+	t, err := parseRecordTime(timeStr)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("error parsing heartbeat time: %v", err)
+	}
+	return t, true, nil
+}
+
+func (s *boltStore) SetHeartbeat(t time.Time) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltHeartbeatBucket)).Put(boltHeartbeatKey, []byte(t.UTC().Format(time.RFC3339)))
+	})
+}
+
+func (s *boltStore) GetTarget(category string) (Target, bool, error) {
+	var raw []byte
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		if v := tx.Bucket([]byte(boltTargetsBucket)).Get([]byte(targetStoreKey(category))); v != nil {
+			raw = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	if err != nil {
+		return Target{}, false, err
+	}
+	if raw == nil {
+		return Target{}, false, nil
+	}
+	seconds, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return Target{}, false, fmt.Errorf("error parsing target for %q: %v", category, err)
+	}
+	return Target{Category: category, Daily: time.Duration(seconds) * time.Second}, true, nil
+}
+
+func (s *boltStore) SetTarget(target Target) error {
+	seconds := int64(target.Daily / time.Second)
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltTargetsBucket)).Put(
+			[]byte(targetStoreKey(target.Category)), []byte(strconv.FormatInt(seconds, 10)),
+		)
+	})
+}
+
+func (s *boltStore) ListTargets() ([]Target, error) {
+	var targets []Target
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltTargetsBucket)).ForEach(func(k, v []byte) error {
+			seconds, err := strconv.ParseInt(string(v), 10, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing target for %q: %v", k, err)
+			}
+			targets = append(targets, Target{Category: targetCategoryFromKey(string(k)), Daily: time.Duration(seconds) * time.Second})
+			return nil
+		})
+	})
+	return targets, err
+}
+
+func (s *boltStore) DeleteTarget(category string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltTargetsBucket)).Delete([]byte(targetStoreKey(category)))
+	})
+}
+
+func (s *boltStore) Close() error {
+	err := s.db.Close()
+	releaseLockFile(s.lockFile)
+	return err
+}
+
+func putRecordTx(tx *bbolt.Tx, record Record) error {
+	key := recordKey(record.time, record.id)
+	value, err := json.Marshal(recordToDTO(record))
+	if err != nil {
+		return fmt.Errorf("error encoding record: %v", err)
+	}
+	if err := tx.Bucket([]byte(boltRecordsBucket)).Put(key, value); err != nil {
+		return err
+	}
+	catKey := categoryKey(record.category, key)
+	if err := tx.Bucket([]byte(boltCategoryBucket)).Put(catKey, key); err != nil {
+		return err
+	}
+	return tx.Bucket([]byte(boltIDBucket)).Put(idKey(record.id), key)
+}
+
+func deleteRecordTx(tx *bbolt.Tx, id int) error {
+	byID := tx.Bucket([]byte(boltIDBucket))
+	key := byID.Get(idKey(id))
+	if key == nil {
+		return fmt.Errorf("no record with id %d", id)
+	}
+	records := tx.Bucket([]byte(boltRecordsBucket))
+	var dto boltRecordDTO
+	if raw := records.Get(key); raw != nil {
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return fmt.Errorf("error decoding record: %v", err)
+		}
+	}
+	if err := records.Delete(key); err != nil {
+		return err
+	}
+	catKey := categoryKey(dto.Category, key)
+	if err := tx.Bucket([]byte(boltCategoryBucket)).Delete(catKey); err != nil {
+		return err
+	}
+	return byID.Delete(idKey(id))
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoltStoreCRUD(t *testing.T) {
+	app, cleanup := setupTestBoltDB(t)
+	defer cleanup()
+
+	id, err := app.Store.WriteRow(Record{action: clockInAction, category: "work"})
+	assert.NoError(t, err)
+	assert.NotZero(t, id)
+
+	records, err := app.Store.ReadRows(1)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "work", records[0].category)
+
+	err = app.Store.UpdateRow(Record{id: id, time: records[0].time, action: clockInAction, category: "personal"})
+	assert.NoError(t, err)
+	records, err = app.Store.ReadRows(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "personal", records[0].category)
+
+	assert.NoError(t, app.Store.DeleteRow(id))
+	records, err = app.Store.ReadRows(1)
+	assert.NoError(t, err)
+	assert.Len(t, records, 0)
+}
+
+func TestBoltStoreRangeByTime(t *testing.T) {
+	app, cleanup := setupTestBoltDB(t)
+	defer cleanup()
+
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertRecordAt(t, app, base, clockInAction, "work")
+	insertRecordAt(t, app, base.Add(time.Hour), clockOutAction, "work")
+	insertRecordAt(t, app, base.Add(2*time.Hour), clockInAction, "personal")
+
+	records, err := app.Store.RangeByTime(base.Add(-time.Hour), base.Add(3*time.Hour), "")
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	records, err = app.Store.RangeByTime(base.Add(-time.Hour), base.Add(3*time.Hour), "work")
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	records, err = app.Store.RangeByTime(base.Add(3*time.Hour), base.Add(4*time.Hour), "")
+	assert.NoError(t, err)
+	assert.Len(t, records, 0)
+}
+
+func TestBoltStoreRangeByTimeCategoryWithPipe(t *testing.T) {
+	app, cleanup := setupTestBoltDB(t)
+	defer cleanup()
+
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertRecordAt(t, app, base, clockInAction, "a")
+	insertRecordAt(t, app, base.Add(time.Hour), clockOutAction, "a")
+	// "a|2026-01-01T10:00:00Z..." would fall inside a query scoped to
+	// category "a" under the old plain "category|" key encoding.
+	insertRecordAt(t, app, base.Add(2*time.Hour), clockInAction, "a|2026-01-01T10:00:00Z")
+	insertRecordAt(t, app, base.Add(3*time.Hour), clockOutAction, "a|2026-01-01T10:00:00Z")
+
+	records, err := app.Store.RangeByTime(base.Add(-time.Hour), base.Add(4*time.Hour), "a")
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	for _, r := range records {
+		assert.Equal(t, "a", r.category)
+	}
+}
+
+func TestBoltStoreHeartbeat(t *testing.T) {
+	app, cleanup := setupTestBoltDB(t)
+	defer cleanup()
+
+	_, ok, err := app.Store.GetHeartbeat()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	when := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	assert.NoError(t, app.Store.SetHeartbeat(when))
+
+	got, ok, err := app.Store.GetHeartbeat()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, when.Equal(got))
+
+	assert.NoError(t, app.Store.SetHeartbeat(when.Add(time.Hour)))
+	got, ok, err = app.Store.GetHeartbeat()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, when.Add(time.Hour).Equal(got))
+}
+
+func TestBoltStoreTargetCRUD(t *testing.T) {
+	app, cleanup := setupTestBoltDB(t)
+	defer cleanup()
+
+	_, ok, err := app.Store.GetTarget("work")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, app.Store.SetTarget(Target{Category: "work", Daily: 8 * time.Hour}))
+	assert.NoError(t, app.Store.SetTarget(Target{Category: "", Daily: 10 * time.Hour}))
+
+	target, ok, err := app.Store.GetTarget("work")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 8*time.Hour, target.Daily)
+
+	allCategories, ok, err := app.Store.GetTarget("")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Hour, allCategories.Daily)
+
+	assert.NoError(t, app.Store.SetTarget(Target{Category: "work", Daily: 6 * time.Hour}))
+	target, _, err = app.Store.GetTarget("work")
+	assert.NoError(t, err)
+	assert.Equal(t, 6*time.Hour, target.Daily)
+
+	targets, err := app.Store.ListTargets()
+	assert.NoError(t, err)
+	assert.Len(t, targets, 2)
+
+	assert.NoError(t, app.Store.DeleteTarget("work"))
+	_, ok, err = app.Store.GetTarget("work")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestMigrateStoresSQLiteToBolt(t *testing.T) {
+	src, cleanupSrc := setupTestDB(t)
+	defer cleanupSrc()
+	dst, cleanupDst := setupTestBoltDB(t)
+	defer cleanupDst()
+
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	insertRecordAt(t, src, base, clockInAction, "work")
+	insertRecordAt(t, src, base.Add(time.Hour), clockOutAction, "work")
+
+	n, err := MigrateStores(src.Store, dst.Store)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	records, err := dst.Store.ReadRows(10)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+}
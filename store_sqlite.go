@@ -0,0 +1,198 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteStore is the Store implementation backed by a SQLite database,
+// the original (and default) storage engine.
+type sqliteStore struct {
+	db   *sql.DB
+	path string
+}
+
+func openSQLiteStore() (*sqliteStore, error) {
+	path, err := ensureBackendPath("clock.db")
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %v", err)
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+	return &sqliteStore{db: db, path: path}, nil
+}
+
+func (s *sqliteStore) ReadRows(n int) ([]Record, error) {
+	rows, err := s.db.Query(
+		"select id, time, action, category from records order by id desc limit ?;",
+		n,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting last %d records: %v", n, err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var record Record
+		if err := rows.Scan(&record.id, &record.time, &record.action, &record.category); err != nil {
+			return nil, fmt.Errorf("error scanning record: %v", err)
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (s *sqliteStore) WriteRow(record Record) (int, error) {
+	if record.time == "" {
+		record.time = time.Now().UTC().Format(time.RFC3339)
+	}
+	res, err := s.db.Exec(
+		"insert into records (time, action, category) values (?, ?, ?);",
+		record.time, record.action, record.category,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting record: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error reading new record id: %v", err)
+	}
+	return int(id), nil
+}
+
+func (s *sqliteStore) UpdateRow(record Record) error {
+	_, err := s.db.Exec(
+		"update records set time = ?, action = ?, category = ? where id = ?;",
+		record.time, record.action, record.category, record.id,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating record %d: %v", record.id, err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) DeleteRow(id int) error {
+	_, err := s.db.Exec("delete from records where id = ?;", id)
+	if err != nil {
+		return fmt.Errorf("error deleting record %d: %v", id, err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) RangeByTime(from, to time.Time, category string) ([]Record, error) {
+	query := "select id, time, action, category from records where time between ? and ?"
+	args := []any{from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339)}
+	if category != "" {
+		query += " and category = ?"
+		args = append(args, category)
+	}
+	query += " order by time asc;"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning records: %v", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var record Record
+		if err := rows.Scan(&record.id, &record.time, &record.action, &record.category); err != nil {
+			return nil, fmt.Errorf("error scanning record: %v", err)
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (s *sqliteStore) GetHeartbeat() (time.Time, bool, error) {
+	var timeStr string
+	err := s.db.QueryRow("select time from heartbeats where id = 1;").Scan(&timeStr)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("error reading heartbeat: %v", err)
+	}
+	t, err := parseRecordTime(timeStr)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("error parsing heartbeat time: %v", err)
+	}
+	return t, true, nil
+}
+
+func (s *sqliteStore) SetHeartbeat(t time.Time) error {
+	_, err := s.db.Exec(
+		"insert into heartbeats (id, time) values (1, ?) on conflict(id) do update set time = excluded.time;",
+		t.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("error recording heartbeat: %v", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) GetTarget(category string) (Target, bool, error) {
+	var seconds int64
+	err := s.db.QueryRow(
+		"select daily_seconds from targets where category = ?;", targetStoreKey(category),
+	).Scan(&seconds)
+	if err == sql.ErrNoRows {
+		return Target{}, false, nil
+	}
+	if err != nil {
+		return Target{}, false, fmt.Errorf("error reading target for %q: %v", category, err)
+	}
+	return Target{Category: category, Daily: time.Duration(seconds) * time.Second}, true, nil
+}
+
+func (s *sqliteStore) SetTarget(target Target) error {
+	_, err := s.db.Exec(
+		"insert into targets (category, daily_seconds) values (?, ?) on conflict(category) do update set daily_seconds = excluded.daily_seconds;",
+		targetStoreKey(target.Category), int64(target.Daily/time.Second),
+	)
+	if err != nil {
+		return fmt.Errorf("error setting target for %q: %v", target.Category, err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) ListTargets() ([]Target, error) {
+	rows, err := s.db.Query("select category, daily_seconds from targets order by category asc;")
+	if err != nil {
+		return nil, fmt.Errorf("error listing targets: %v", err)
+	}
+	defer rows.Close()
+
+	var targets []Target
+	for rows.Next() {
+		var key string
+		var seconds int64
+		if err := rows.Scan(&key, &seconds); err != nil {
+			return nil, fmt.Errorf("error scanning target: %v", err)
+		}
+		targets = append(targets, Target{Category: targetCategoryFromKey(key), Daily: time.Duration(seconds) * time.Second})
+	}
+	return targets, rows.Err()
+}
+
+func (s *sqliteStore) DeleteTarget(category string) error {
+	_, err := s.db.Exec("delete from targets where category = ?;", targetStoreKey(category))
+	if err != nil {
+		return fmt.Errorf("error deleting target for %q: %v", category, err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
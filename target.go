@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Target is a daily time goal for a category; an empty Category applies
+// across all categories combined.
+type Target struct {
+	Category string
+	Daily    time.Duration
+}
+
+// TargetProgress is how much of a Target's daily goal has been reached.
+type TargetProgress struct {
+	Target  Target
+	Elapsed time.Duration
+}
+
+// Percent returns Elapsed as a percentage of the target's daily duration,
+// capped at 100.
+func (p TargetProgress) Percent() float64 {
+	if p.Target.Daily <= 0 {
+		return 0
+	}
+	pct := p.Elapsed.Seconds() / p.Target.Daily.Seconds() * 100
+	if pct > 100 {
+		pct = 100
+	}
+	return pct
+}
+
+// ProgressBar renders p as e.g. "6h12m / 8h00m, 78%".
+func (p TargetProgress) ProgressBar() string {
+	return fmt.Sprintf("%s / %s, %.0f%%", formatDuration(p.Elapsed), formatDuration(p.Target.Daily), p.Percent())
+}
+
+// targetForCategory returns the configured target for category, falling
+// back to the all-categories target if no category-specific one exists.
+func (app *ClockApp) targetForCategory(category string) (Target, bool, error) {
+	if category != "" {
+		target, ok, err := app.Store.GetTarget(category)
+		if err != nil {
+			return Target{}, false, err
+		}
+		if ok {
+			return target, true, nil
+		}
+	}
+	return app.Store.GetTarget("")
+}
+
+// TargetProgressToday reports progress against target using time clocked
+// today (in loc) for target.Category, or every category combined if
+// target.Category is "".
+func (app *ClockApp) TargetProgressToday(target Target, loc *time.Location) (TargetProgress, error) {
+	now := time.Now().In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	report, err := app.Report(start, end, target.Category)
+	if err != nil {
+		return TargetProgress{}, fmt.Errorf("error computing target progress: %v", err)
+	}
+
+	var elapsed time.Duration
+	for _, total := range report.Totals {
+		elapsed += total.Duration
+	}
+	return TargetProgress{Target: target, Elapsed: elapsed}, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetProgressPercent(t *testing.T) {
+	progress := TargetProgress{Target: Target{Category: "work", Daily: 8 * time.Hour}, Elapsed: 6*time.Hour + 12*time.Minute}
+	assert.InDelta(t, 77.5, progress.Percent(), 0.5)
+	assert.Equal(t, "6h12m / 8h00m, 78%", progress.ProgressBar())
+}
+
+func TestTargetProgressPercentCapsAt100(t *testing.T) {
+	progress := TargetProgress{Target: Target{Daily: time.Hour}, Elapsed: 2 * time.Hour}
+	assert.Equal(t, 100.0, progress.Percent())
+}
+
+func TestTargetForCategoryFallsBackToAllCategories(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	assert.NoError(t, app.Store.SetTarget(Target{Category: "", Daily: 8 * time.Hour}))
+
+	target, ok, err := app.targetForCategory("work")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 8*time.Hour, target.Daily)
+
+	assert.NoError(t, app.Store.SetTarget(Target{Category: "work", Daily: 4 * time.Hour}))
+	target, ok, err = app.targetForCategory("work")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 4*time.Hour, target.Daily)
+}
+
+func TestTargetProgressToday(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.UTC)
+	insertRecordAt(t, app, start, clockInAction, "work")
+	insertRecordAt(t, app, start.Add(2*time.Hour), clockOutAction, "work")
+
+	progress, err := app.TargetProgressToday(Target{Category: "work", Daily: 8 * time.Hour}, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, progress.Elapsed)
+}
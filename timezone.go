@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// displayTimeLayout is how times are rendered to the user once resolved
+// to the configured display timezone.
+const displayTimeLayout = "2006-01-02 15:04:05 MST"
+
+// legacyTimeLayout is the naive, offset-less format records were stored
+// in before timezone awareness was added. It's kept around so
+// parseRecordTime and MigrateLegacyTimes can still make sense of old rows.
+const legacyTimeLayout = "2006-01-02 15:04:05"
+
+// resolveLocation picks the timezone used for display: an explicit flag
+// wins, then CLOCK_TZ, then the system's local timezone.
+func resolveLocation(flag string) (*time.Location, error) {
+	name := flag
+	if name == "" {
+		name = os.Getenv("CLOCK_TZ")
+	}
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("error loading timezone %q: %v", name, err)
+	}
+	return loc, nil
+}
+
+// parseRecordTime parses a record's stored time string. Current records
+// are written in RFC3339 with an offset; rows written before timezone
+// awareness was added are naive "2006-01-02 15:04:05" strings, which are
+// interpreted as local time for backward compatibility.
+func parseRecordTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(legacyTimeLayout, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing record time %q: %v", s, err)
+	}
+	return t, nil
+}
+
+// isLegacyRecordTime reports whether s is a pre-timezone naive timestamp
+// rather than RFC3339.
+func isLegacyRecordTime(s string) bool {
+	_, err := time.Parse(time.RFC3339, s)
+	return err != nil
+}
+
+// MigrateLegacyTimes rewrites any record whose time is a naive,
+// pre-timezone-awareness string into RFC3339 (UTC), assuming the naive
+// time was local. NewClockApp runs it each time it opens a store, so every
+// record the other commands operate on is already RFC3339; the migrate
+// command opens its stores directly and copies times as they are stored.
+func MigrateLegacyTimes(store Store) (int, error) {
+	records, err := store.ReadRows(1 << 30)
+	if err != nil {
+		return 0, fmt.Errorf("error scanning records for timezone migration: %v", err)
+	}
+	n := 0
+	for _, record := range records {
+		if !isLegacyRecordTime(record.time) {
+			continue
+		}
+		t, err := time.ParseInLocation(legacyTimeLayout, record.time, time.Local)
+		if err != nil {
+			return n, fmt.Errorf("error parsing legacy time %q: %v", record.time, err)
+		}
+		record.time = t.UTC().Format(time.RFC3339)
+		if err := store.UpdateRow(record); err != nil {
+			return n, fmt.Errorf("error rewriting record %d: %v", record.id, err)
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveLocation(t *testing.T) {
+	loc, err := resolveLocation("America/New_York")
+	assert.NoError(t, err)
+	assert.Equal(t, "America/New_York", loc.String())
+
+	t.Setenv("CLOCK_TZ", "Asia/Tokyo")
+	loc, err = resolveLocation("")
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+
+	os.Unsetenv("CLOCK_TZ")
+	loc, err = resolveLocation("")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Local, loc)
+
+	_, err = resolveLocation("Not/AZone")
+	assert.Error(t, err)
+}
+
+func TestParseRecordTimeRFC3339(t *testing.T) {
+	want := time.Date(2026, 3, 5, 14, 30, 0, 0, time.UTC)
+	got, err := parseRecordTime(want.Format(time.RFC3339))
+	assert.NoError(t, err)
+	assert.True(t, want.Equal(got))
+}
+
+func TestParseRecordTimeLegacy(t *testing.T) {
+	got, err := parseRecordTime("2026-03-05 14:30:00")
+	assert.NoError(t, err)
+	want, _ := time.ParseInLocation("2006-01-02 15:04:05", "2026-03-05 14:30:00", time.Local)
+	assert.True(t, want.Equal(got))
+}
+
+func TestMigrateLegacyTimes(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	id, err := app.Store.WriteRow(Record{time: "2026-03-05 14:30:00", action: clockInAction, category: "work"})
+	assert.NoError(t, err)
+
+	n, err := MigrateLegacyTimes(app.Store)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	records, err := app.Store.ReadRows(10)
+	assert.NoError(t, err)
+	var migrated Record
+	for _, r := range records {
+		if r.id == id {
+			migrated = r
+		}
+	}
+	assert.False(t, isLegacyRecordTime(migrated.time))
+
+	// Running again should be a no-op.
+	n, err = MigrateLegacyTimes(app.Store)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestResolveReportRangeUsesGivenLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+
+	from, to, err := resolveReportRange("", "", true, false, false, loc)
+	assert.NoError(t, err)
+
+	wantStart := time.Date(time.Now().In(loc).Year(), time.Now().In(loc).Month(), time.Now().In(loc).Day(), 0, 0, 0, 0, loc)
+	assert.True(t, wantStart.Equal(from))
+	assert.Equal(t, "Asia/Tokyo", to.Location().String())
+}
+
+func TestParseEditFlagsUsesGivenLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+
+	newTime, _, _, err := parseEditFlags("2026-03-05 14:30:00", "", "", loc)
+	assert.NoError(t, err)
+	want, _ := time.ParseInLocation(legacyTimeLayout, "2026-03-05 14:30:00", loc)
+	assert.True(t, want.Equal(*newTime))
+}
+
+func TestReportAcrossNonUTCZone(t *testing.T) {
+	app, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	loc, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	base := time.Date(2026, 3, 5, 9, 0, 0, 0, loc)
+	insertRecordAt(t, app, base, clockInAction, "work")
+	insertRecordAt(t, app, base.Add(2*time.Hour), clockOutAction, "work")
+
+	report, err := app.Report(base.Add(-time.Hour), base.Add(3*time.Hour), "")
+	assert.NoError(t, err)
+	assert.Len(t, report.Totals, 1)
+	assert.Equal(t, 2*time.Hour, report.Totals[0].Duration)
+}